@@ -0,0 +1,152 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicsdriver
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// graphicsLibraryEnvVar is the environment variable games and tooling can set to force Auto to a
+// specific backend, e.g. EBITENGINE_GRAPHICS_LIBRARY=vulkan, bypassing the usual priority order.
+const graphicsLibraryEnvVar = "EBITENGINE_GRAPHICS_LIBRARY"
+
+// selectedLibrary returns the backend name requested via EBITENGINE_GRAPHICS_LIBRARY, and whether
+// the environment variable was set at all.
+func selectedLibrary() (string, bool) {
+	name := os.Getenv(graphicsLibraryEnvVar)
+	return name, name != ""
+}
+
+// Preferences describes the capabilities a game requires, or would like, from whichever backend
+// Auto selects.
+type Preferences struct {
+	// RequireCompute excludes backends that cannot dispatch compute shaders.
+	RequireCompute bool
+
+	// RequireSRGBFramebuffer excludes backends that cannot blend directly into an sRGB
+	// framebuffer.
+	RequireSRGBFramebuffer bool
+
+	// RequireStorageImages excludes backends whose compute shaders cannot bind storage images.
+	RequireStorageImages bool
+
+	// RequireTimestampQueries excludes backends that cannot report per-pass GPU timings.
+	RequireTimestampQueries bool
+}
+
+func (p Preferences) satisfiedBy(c Capabilities) bool {
+	if p.RequireCompute && !c.Compute {
+		return false
+	}
+	if p.RequireSRGBFramebuffer && !c.SRGBFramebuffer {
+		return false
+	}
+	if p.RequireStorageImages && !c.StorageImages {
+		return false
+	}
+	if p.RequireTimestampQueries && !c.TimestampQueries {
+		return false
+	}
+	return true
+}
+
+// backend pairs a Graphics constructor with the name reported in Auto's error message and
+// accepted by the EBITENGINE_GRAPHICS_LIBRARY environment variable.
+type backend struct {
+	name string
+	new  func() (Graphics, error)
+}
+
+// backends lists the registered candidates in priority order: Vulkan and Metal are modern,
+// lower-overhead APIs and are preferred over OpenGL, which every platform Ebitengine supports can
+// fall back to.
+var backends []backend
+
+// backendPriority ranks backend names for Auto; lower ranks are tried first. Names not listed
+// here rank between metal and opengl.
+var backendPriority = map[string]int{"vulkan": 0, "metal": 1, "opengl": 3}
+
+func backendRank(name string) int {
+	if r, ok := backendPriority[name]; ok {
+		return r
+	}
+	return 2
+}
+
+// RegisterBackend adds a backend to the list Auto considers, keeping the list sorted by
+// backendPriority; backends of equal rank stay in registration order. Each backend package calls
+// this from an init function, so graphicsdriver itself never needs to import build-tag-gated
+// backend packages directly.
+func RegisterBackend(name string, new func() (Graphics, error)) {
+	backends = append(backends, backend{name: name, new: new})
+	sort.SliceStable(backends, func(i, j int) bool {
+		return backendRank(backends[i].name) < backendRank(backends[j].name)
+	})
+}
+
+// Auto enumerates the registered backends in priority order and returns the first one that both
+// initializes successfully and whose Capabilities satisfy prefs. This replaces choosing a backend
+// solely by which one was compiled in via build tags.
+//
+// If EBITENGINE_GRAPHICS_LIBRARY is set, Auto tries only the backend it names instead of walking
+// the priority order, returning an error if that backend doesn't exist, doesn't initialize, or
+// doesn't satisfy prefs.
+func Auto(prefs Preferences) (Graphics, error) {
+	if name, ok := selectedLibrary(); ok {
+		return autoSelected(name, prefs)
+	}
+
+	var reasons []string
+	for _, b := range backends {
+		g, err := b.new()
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("%s: %v", b.name, err))
+			continue
+		}
+		if g == nil {
+			reasons = append(reasons, fmt.Sprintf("%s: returned no Graphics and no error", b.name))
+			continue
+		}
+		if !prefs.satisfiedBy(g.Capabilities()) {
+			reasons = append(reasons, fmt.Sprintf("%s: does not satisfy the given preferences", b.name))
+			continue
+		}
+		return g, nil
+	}
+	return nil, fmt.Errorf("graphicsdriver: no backend is available (%s)", strings.Join(reasons, "; "))
+}
+
+func autoSelected(name string, prefs Preferences) (Graphics, error) {
+	for _, b := range backends {
+		if b.name != name {
+			continue
+		}
+		g, err := b.new()
+		if err != nil {
+			return nil, fmt.Errorf("graphicsdriver: %s=%q failed to initialize: %w", graphicsLibraryEnvVar, name, err)
+		}
+		if g == nil {
+			return nil, fmt.Errorf("graphicsdriver: %s=%q returned no Graphics and no error", graphicsLibraryEnvVar, name)
+		}
+		if !prefs.satisfiedBy(g.Capabilities()) {
+			return nil, fmt.Errorf("graphicsdriver: %s=%q does not satisfy the given preferences", graphicsLibraryEnvVar, name)
+		}
+		return g, nil
+	}
+	return nil, fmt.Errorf("graphicsdriver: %s=%q does not match any registered backend", graphicsLibraryEnvVar, name)
+}
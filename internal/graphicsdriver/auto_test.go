@@ -0,0 +1,128 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicsdriver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPreferencesSatisfiedBy(t *testing.T) {
+	tests := []struct {
+		name  string
+		prefs Preferences
+		caps  Capabilities
+		want  bool
+	}{
+		{
+			name:  "no requirements",
+			prefs: Preferences{},
+			caps:  Capabilities{},
+			want:  true,
+		},
+		{
+			name:  "compute required and present",
+			prefs: Preferences{RequireCompute: true},
+			caps:  Capabilities{Compute: true},
+			want:  true,
+		},
+		{
+			name:  "compute required but missing",
+			prefs: Preferences{RequireCompute: true},
+			caps:  Capabilities{Compute: false},
+			want:  false,
+		},
+		{
+			name:  "sRGB framebuffer required but missing",
+			prefs: Preferences{RequireSRGBFramebuffer: true},
+			caps:  Capabilities{Compute: true},
+			want:  false,
+		},
+		{
+			name:  "storage images and timestamp queries both satisfied",
+			prefs: Preferences{RequireStorageImages: true, RequireTimestampQueries: true},
+			caps:  Capabilities{StorageImages: true, TimestampQueries: true},
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.prefs.satisfiedBy(tt.caps); got != tt.want {
+				t.Errorf("satisfiedBy: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectedLibrary(t *testing.T) {
+	t.Setenv(graphicsLibraryEnvVar, "")
+	if _, ok := selectedLibrary(); ok {
+		t.Error("selectedLibrary with unset env var: got ok, want !ok")
+	}
+
+	t.Setenv(graphicsLibraryEnvVar, "vulkan")
+	name, ok := selectedLibrary()
+	if !ok {
+		t.Fatal("selectedLibrary with env var set: got !ok, want ok")
+	}
+	if got, want := name, "vulkan"; got != want {
+		t.Errorf("selectedLibrary: got %q, want %q", got, want)
+	}
+}
+
+func TestAutoUsesSelectedLibrary(t *testing.T) {
+	orig := backends
+	t.Cleanup(func() { backends = orig })
+	backends = nil
+
+	RegisterBackend("fake-a", func() (Graphics, error) { return nil, nil })
+	RegisterBackend("fake-b", func() (Graphics, error) { return nil, nil })
+
+	t.Setenv(graphicsLibraryEnvVar, "does-not-exist")
+	_, err := Auto(Preferences{})
+	if err == nil {
+		t.Fatal("Auto with an unknown EBITENGINE_GRAPHICS_LIBRARY: got nil error, want non-nil")
+	}
+	if !strings.Contains(err.Error(), "does-not-exist") {
+		t.Errorf("Auto error %q does not name the backend EBITENGINE_GRAPHICS_LIBRARY selected", err)
+	}
+}
+
+// TestAutoRejectsNilGraphicsInPriorityOrder drives Auto's no-env-var priority-order path (as
+// opposed to TestAutoUsesSelectedLibrary, which only exercises autoSelected) against a backend
+// that returns (nil, nil), a legitimate-if-unusual constructor result. Before Auto's nil check,
+// this panicked on g.Capabilities() instead of moving on to the next backend.
+func TestAutoRejectsNilGraphicsInPriorityOrder(t *testing.T) {
+	orig := backends
+	t.Cleanup(func() { backends = orig })
+	backends = nil
+
+	RegisterBackend("fake-nil", func() (Graphics, error) { return nil, nil })
+	RegisterBackend("fake-err", func() (Graphics, error) { return nil, errors.New("fake-err: init failed") })
+
+	t.Setenv(graphicsLibraryEnvVar, "")
+	_, err := Auto(Preferences{})
+	if err == nil {
+		t.Fatal("Auto with only nil/erroring backends registered: got nil error, want non-nil")
+	}
+	if !strings.Contains(err.Error(), "fake-nil") {
+		t.Errorf("Auto error %q does not mention the backend that returned a nil Graphics", err)
+	}
+	if !strings.Contains(err.Error(), "fake-err") {
+		t.Errorf("Auto error %q does not mention the backend that returned an error", err)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicsdriver
+
+// Capabilities describes what a Graphics implementation supports, so that code choosing between
+// backends doesn't need to special-case each one individually.
+type Capabilities struct {
+	// MaxTextureSize is the maximum width or height of a texture the backend can create.
+	MaxTextureSize int
+
+	// MaxTextureUnits is the number of texture units that can be bound to a single draw call.
+	MaxTextureUnits int
+
+	// MaxSamples is the maximum number of MSAA sample counts the backend can render with, or 1
+	// if the backend doesn't support multisampling at all.
+	MaxSamples int
+
+	// UniformBufferOffsetAlignment is the byte alignment required between successive bindings
+	// into a single uniform buffer, or 0 if the backend has no such buffer.
+	UniformBufferOffsetAlignment int
+
+	// SRGBFramebuffer reports whether the backend can blend directly into an sRGB-encoded
+	// framebuffer (see ColorSpaceLinear). Backends without it require a shader-side fallback.
+	SRGBFramebuffer bool
+
+	// Compute reports whether the backend can dispatch compute shaders.
+	Compute bool
+
+	// StorageImages reports whether compute shaders can bind an image for imageLoad/imageStore.
+	StorageImages bool
+
+	// AsyncFences reports whether the backend can pipeline framebuffer readback with fences
+	// instead of stalling the GPU on every read.
+	AsyncFences bool
+
+	// TimestampQueries reports whether the backend can report per-pass GPU timings.
+	TimestampQueries bool
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphicsdriver
+
+// ColorSpace represents the color space an Image's pixels, or a Framebuffer's contents, are
+// stored and blended in.
+type ColorSpace int
+
+const (
+	// ColorSpaceSRGB blends and stores colors in the nonlinear sRGB space Ebitengine has always
+	// used. This is the default.
+	ColorSpaceSRGB ColorSpace = iota
+
+	// ColorSpaceLinear blends and stores colors in linear light, correcting the "dark fringe"
+	// that gradients and alpha compositing otherwise show when performed directly on
+	// gamma-encoded sRGB values.
+	ColorSpaceLinear
+)
@@ -23,6 +23,7 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
 	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/opengl/gl"
 	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir/glsl"
 )
 
 type blendFactor int
@@ -105,6 +106,9 @@ type (
 	attribLocation  int32
 )
 
+// glSync wraps a GLsync fence used to detect completion of an asynchronous glReadPixels.
+type glSync uintptr
+
 const (
 	invalidFramebuffer = (1 << 32) - 1
 	invalidUniform     = -1
@@ -127,9 +131,81 @@ type context struct {
 	highpOnce          sync.Once
 	initOnce           sync.Once
 
+	srgbFramebufferSupported     bool
+	srgbFramebufferSupportedOnce sync.Once
+	lastFramebufferSRGBEnabled   bool
+	framebufferColorSpaces       map[framebufferNative]graphicsdriver.ColorSpace
+
+	srgbTextureSupported     bool
+	srgbTextureSupportedOnce sync.Once
+	manualSRGBDecodeTextures map[textureNative]bool
+
+	computeSupported     bool
+	computeSupportedOnce sync.Once
+
+	asyncReadbackSupported     bool
+	asyncReadbackSupportedOnce sync.Once
+	pixelBufferRing            [pixelBufferRingSize]pixelBufferRingEntry
+
+	timestampQueriesSupported     bool
+	timestampQueriesSupportedOnce sync.Once
+
+	maxTextureUnits     int
+	maxTextureUnitsOnce sync.Once
+
 	contextImpl
 }
 
+// capabilities gathers this context's probed capabilities into the common
+// graphicsdriver.Capabilities descriptor, so that graphicsdriver.Auto can compare this backend
+// against others without the opengl package exposing each probe individually.
+func (c *context) capabilities() graphicsdriver.Capabilities {
+	return graphicsdriver.Capabilities{
+		MaxTextureSize:   c.getMaxTextureSize(),
+		MaxTextureUnits:  c.getMaxTextureUnits(),
+		MaxSamples:       1,
+		SRGBFramebuffer:  c.isSRGBFramebufferSupported(),
+		Compute:          c.isComputeSupported(),
+		StorageImages:    c.isComputeSupported(),
+		AsyncFences:      c.isAsyncReadbackSupported(),
+		TimestampQueries: c.isTimestampQueriesSupported(),
+	}
+}
+
+func (c *context) getMaxTextureUnits() int {
+	c.maxTextureUnitsOnce.Do(func() {
+		c.maxTextureUnits = c.ctx.GetInteger(gl.MAX_TEXTURE_IMAGE_UNITS)
+	})
+	return c.maxTextureUnits
+}
+
+// isTimestampQueriesSupported reports whether GPU_TIMER-style per-pass timings are available via
+// glGenQueries/glBeginQuery(GL_TIME_ELAPSED).
+func (c *context) isTimestampQueriesSupported() bool {
+	c.timestampQueriesSupportedOnce.Do(func() {
+		c.timestampQueriesSupported = c.timestampQueriesSupportedImpl()
+	})
+	return c.timestampQueriesSupported
+}
+
+// pixelBufferRingSize is the number of in-flight GL_PIXEL_PACK_BUFFERs framebufferPixelsAsync
+// cycles through. A handful of frames of slack is enough to absorb the GPU/CPU latency a fence
+// wait introduces without the ring growing unbounded.
+const pixelBufferRingSize = 3
+
+// pixelBufferRingEntry tracks one slot of the PBO ring used by framebufferPixelsAsync: the GPU
+// buffer object, the fence marking when its last glReadPixels finished, and where to deliver the
+// result once the fence is signaled.
+type pixelBufferRingEntry struct {
+	buffer buffer
+	size   int
+
+	pending bool
+	fence   glSync
+	dst     []byte
+	ch      chan<- error
+}
+
 func (c *context) bindTexture(t textureNative) {
 	if c.lastTexture == t {
 		return
@@ -156,6 +232,11 @@ func (c *context) bindFramebuffer(f framebufferNative) {
 
 func (c *context) setViewport(f *framebuffer) {
 	c.bindFramebuffer(f.native)
+
+	// GL_FRAMEBUFFER_SRGB is global GL state, not a per-framebuffer-object setting, so it must be
+	// set to match whichever framebuffer is bound right before issuing draw calls into it.
+	c.setFramebufferSRGBEnabled(c.framebufferColorSpaces[f.native] == graphicsdriver.ColorSpaceLinear && c.isSRGBFramebufferSupported())
+
 	if c.lastViewportWidth != f.width || c.lastViewportHeight != f.height {
 		// On some environments, viewport size must be within the framebuffer size.
 		// e.g. Edge (#71), Chrome on GPD Pocket (#420), macOS Mojave (#691).
@@ -186,6 +267,27 @@ func (c *context) getMaxTextureSize() int {
 	return c.maxTextureSize
 }
 
+// isSRGBFramebufferSupported reports whether GL_FRAMEBUFFER_SRGB is available. Drivers without it
+// (notably GLES2) must fall back to shader-side encode/decode instead of relying on fixed-function
+// sRGB blending.
+func (c *context) isSRGBFramebufferSupported() bool {
+	c.srgbFramebufferSupportedOnce.Do(func() {
+		c.srgbFramebufferSupported = c.srgbFramebufferSupportedImpl()
+	})
+	return c.srgbFramebufferSupported
+}
+
+// isSRGBTextureSupported reports whether a texture can be created with the GL_SRGB8_ALPHA8
+// internal format so that sampling it decodes sRGB-encoded bytes automatically. Drivers without
+// it must store linear-space textures as plain GL_RGBA and rely on the caller to have the GLSL
+// emitter inject the pow(x, 2.2) decode instead.
+func (c *context) isSRGBTextureSupported() bool {
+	c.srgbTextureSupportedOnce.Do(func() {
+		c.srgbTextureSupported = c.srgbTextureSupportedImpl()
+	})
+	return c.srgbTextureSupported
+}
+
 func (c *context) reset() error {
 	var err1 error
 	c.initOnce.Do(func() {
@@ -211,9 +313,30 @@ func (c *context) reset() error {
 	c.blend(graphicsdriver.BlendSourceOver)
 	c.screenFramebuffer = framebufferNative(c.ctx.GetInteger(gl.FRAMEBUFFER_BINDING))
 	// TODO: Need to update screenFramebufferWidth/Height?
+
+	c.lastFramebufferSRGBEnabled = false
+	c.framebufferColorSpaces = map[framebufferNative]graphicsdriver.ColorSpace{}
+	c.manualSRGBDecodeTextures = map[textureNative]bool{}
+
 	return nil
 }
 
+// setFramebufferSRGBEnabled enables or disables GL_FRAMEBUFFER_SRGB, which tells drivers that
+// support it to linearize before blending and re-encode to sRGB on write. It is only meaningful
+// when isSRGBFramebufferSupported reports true; callers on drivers without it must instead inject
+// the shader-side encode/decode that the GLSL emitter produces for graphicsdriver.ColorSpaceLinear.
+func (c *context) setFramebufferSRGBEnabled(enabled bool) {
+	if c.lastFramebufferSRGBEnabled == enabled {
+		return
+	}
+	c.lastFramebufferSRGBEnabled = enabled
+	if enabled {
+		c.ctx.Enable(gl.FRAMEBUFFER_SRGB)
+	} else {
+		c.ctx.Disable(gl.FRAMEBUFFER_SRGB)
+	}
+}
+
 func (c *context) blend(blend graphicsdriver.Blend) {
 	if c.lastBlend == blend {
 		return
@@ -235,7 +358,11 @@ func (c *context) scissor(x, y, width, height int) {
 	c.ctx.Scissor(int32(x), int32(y), int32(width), int32(height))
 }
 
-func (c *context) newTexture(width, height int) (textureNative, error) {
+// newTexture creates a texture of the given size and color space. Whether the texture could not
+// get hardware sRGB decoding, and so needs manualSRGBDecodeNeeded to report true for it, is
+// recorded on c rather than returned, matching how newFramebuffer's colorSpace is looked up later
+// through framebufferColorSpaces rather than threaded through every caller.
+func (c *context) newTexture(width, height int, colorSpace graphicsdriver.ColorSpace) (textureNative, error) {
 	t := c.ctx.CreateTexture()
 	if t <= 0 {
 		return 0, errors.New("opengl: creating texture failed")
@@ -248,6 +375,19 @@ func (c *context) newTexture(width, height int) (textureNative, error) {
 	c.ctx.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
 	c.ctx.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
 
+	internalFormat := int32(gl.RGBA)
+	if colorSpace == graphicsdriver.ColorSpaceLinear {
+		if c.isSRGBTextureSupported() {
+			// GL_SRGB8_ALPHA8 tells the driver the stored bytes are sRGB-encoded, so sampling in
+			// a shader automatically linearizes them. This only affects storage and sampling; it
+			// is independent of GL_FRAMEBUFFER_SRGB, which affects blending into a bound
+			// framebuffer.
+			internalFormat = gl.SRGB8_ALPHA8
+		} else {
+			c.manualSRGBDecodeTextures[textureNative(t)] = true
+		}
+	}
+
 	// Firefox warns the usage of textures without specifying pixels (#629)
 	//
 	//     Error: WebGL warning: drawElements: This operation requires zeroing texture data. This is slow.
@@ -255,11 +395,17 @@ func (c *context) newTexture(width, height int) (textureNative, error) {
 	// In Ebitengine, textures are filled with pixels later by the filter that ignores destination, so it is fine
 	// to leave textures as uninitialized here. Rather, extra memory allocating for initialization should be
 	// avoided.
-	c.ctx.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, nil)
+	c.ctx.TexImage2D(gl.TEXTURE_2D, 0, internalFormat, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, nil)
 
 	return textureNative(t), nil
 }
 
+// manualSRGBDecodeNeeded reports whether sampling t requires the GLSL emitter to inject
+// glsl.ManualSRGBDecode, because newTexture could not get hardware sRGB decoding for it.
+func (c *context) manualSRGBDecodeNeeded(t textureNative) bool {
+	return c.manualSRGBDecodeTextures[t]
+}
+
 func (c *context) bindFramebufferImpl(f framebufferNative) {
 	c.ctx.BindFramebuffer(gl.FRAMEBUFFER, uint32(f))
 }
@@ -285,6 +431,138 @@ func (c *context) framebufferPixelsToBuffer(f *framebuffer, buffer buffer, width
 	c.ctx.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
 }
 
+// isAsyncReadbackSupported reports whether framebufferPixelsAsync can use fenced PBO pipelining.
+// GLES2/WebGL1 have neither ARB_sync nor guaranteed PBO mapping, so those drivers always fall
+// back to a synchronous framebufferPixels.
+func (c *context) isAsyncReadbackSupported() bool {
+	c.asyncReadbackSupportedOnce.Do(func() {
+		c.asyncReadbackSupported = !c.ctx.IsES() && c.arbSyncSupportedImpl()
+	})
+	return c.asyncReadbackSupported
+}
+
+// framebufferPixelsAsync reads f's pixels into buf without stalling the GPU pipeline. Unlike
+// framebufferPixels, the read is not complete when this call returns: it is issued into one of a
+// small ring of pixel-pack buffers guarded by a fence, and pollPixelBufferRing, called at the
+// start of every subsequent call, finishes whichever earlier reads have become ready in the
+// meantime. The returned channel receives exactly one error (nil on success) and is then closed.
+func (c *context) framebufferPixelsAsync(buf []byte, f *framebuffer, x, y, width, height int) <-chan error {
+	ch := make(chan error, 1)
+
+	if got, want := len(buf), 4*width*height; got != want {
+		ch <- fmt.Errorf("opengl: len(buf) must be %d but was %d at framebufferPixelsAsync", want, got)
+		close(ch)
+		return ch
+	}
+
+	if !c.isAsyncReadbackSupported() {
+		ch <- c.framebufferPixels(buf, f, x, y, width, height)
+		close(ch)
+		return ch
+	}
+
+	c.pollPixelBufferRing()
+
+	entry := c.acquirePixelBufferRingEntry(4 * width * height)
+
+	c.ctx.Flush()
+	c.bindFramebuffer(f.native)
+	c.ctx.BindBuffer(gl.PIXEL_PACK_BUFFER, uint32(entry.buffer))
+	c.ctx.ReadPixels(nil, int32(x), int32(y), int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE)
+	c.ctx.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+
+	entry.pending = true
+	entry.fence = c.fenceSyncImpl()
+	entry.dst = buf
+	entry.ch = ch
+
+	return ch
+}
+
+// pickPixelBufferRingSlot decides which ring slot acquirePixelBufferRingEntry should hand out for
+// a read of size bytes, given each slot's current pending/size state, without touching any GL
+// state itself. It returns the chosen index, whether that slot's buffer object needs
+// (re)allocating because it doesn't exist yet or is the wrong size, and whether every slot was
+// still pending, in which case the caller must block on slot 0, which is not necessarily the
+// oldest pending read, and retry rather than growing the ring unboundedly. Extracted from acquirePixelBufferRingEntry so the ring
+// selection policy can be tested without a live GL context.
+func pickPixelBufferRingSlot(pending []bool, sizes []int, size int) (index int, needsRealloc bool, mustBlock bool) {
+	for i, p := range pending {
+		if p {
+			continue
+		}
+		return i, sizes[i] != size, false
+	}
+	return 0, false, true
+}
+
+// acquirePixelBufferRingEntry returns the next ring slot, (re)allocating its buffer object if it
+// doesn't exist yet or is the wrong size. Callers must have already drained any pending read on
+// this slot via pollPixelBufferRing.
+func (c *context) acquirePixelBufferRingEntry(size int) *pixelBufferRingEntry {
+	pending := make([]bool, len(c.pixelBufferRing))
+	sizes := make([]int, len(c.pixelBufferRing))
+	for i := range c.pixelBufferRing {
+		pending[i] = c.pixelBufferRing[i].pending
+		sizes[i] = c.pixelBufferRing[i].size
+	}
+
+	idx, needsRealloc, mustBlock := pickPixelBufferRingSlot(pending, sizes, size)
+	if mustBlock {
+		c.finishPixelBufferRingEntry(&c.pixelBufferRing[0], true)
+		return c.acquirePixelBufferRingEntry(size)
+	}
+
+	e := &c.pixelBufferRing[idx]
+	if needsRealloc {
+		if e.buffer != 0 {
+			c.ctx.DeleteBuffer(uint32(e.buffer))
+		}
+		e.buffer = buffer(c.ctx.CreateBuffer())
+		c.ctx.BindBuffer(gl.PIXEL_PACK_BUFFER, uint32(e.buffer))
+		c.ctx.BufferInit(gl.PIXEL_PACK_BUFFER, size, gl.STREAM_READ)
+		c.ctx.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+		e.size = size
+	}
+	return e
+}
+
+// pollPixelBufferRing checks every pending ring entry's fence without blocking and finishes the
+// ones that are already signaled.
+func (c *context) pollPixelBufferRing() {
+	for i := range c.pixelBufferRing {
+		e := &c.pixelBufferRing[i]
+		if !e.pending {
+			continue
+		}
+		c.finishPixelBufferRingEntry(e, false)
+	}
+}
+
+// finishPixelBufferRingEntry waits for e's fence (blocking only if wait is true, otherwise
+// polling with a zero timeout) and, once signaled, maps e's buffer, copies it into the
+// destination the caller gave framebufferPixelsAsync, and delivers the result.
+func (c *context) finishPixelBufferRingEntry(e *pixelBufferRingEntry, wait bool) {
+	if !c.clientWaitSyncImpl(e.fence, wait) {
+		return
+	}
+
+	c.ctx.BindBuffer(gl.PIXEL_PACK_BUFFER, uint32(e.buffer))
+	mapped := c.mapBufferRangeImpl(gl.PIXEL_PACK_BUFFER, e.size)
+	copy(e.dst, mapped)
+	c.unmapBufferImpl(gl.PIXEL_PACK_BUFFER)
+	c.ctx.BindBuffer(gl.PIXEL_PACK_BUFFER, 0)
+
+	c.deleteSyncImpl(e.fence)
+
+	e.pending = false
+	e.fence = 0
+	e.dst = nil
+	e.ch <- nil
+	close(e.ch)
+	e.ch = nil
+}
+
 func (c *context) activeTexture(idx int) {
 	c.ctx.ActiveTexture(uint32(gl.TEXTURE0 + idx))
 }
@@ -301,6 +579,7 @@ func (c *context) deleteTexture(t textureNative) {
 		c.lastTexture = 0
 	}
 	c.ctx.DeleteTexture(uint32(t))
+	delete(c.manualSRGBDecodeTextures, t)
 }
 
 func (c *context) isTexture(t textureNative) bool {
@@ -335,7 +614,11 @@ func (c *context) deleteRenderbuffer(r renderbufferNative) {
 	c.ctx.DeleteRenderbuffer(uint32(r))
 }
 
-func (c *context) newFramebuffer(texture textureNative) (framebufferNative, error) {
+func (c *context) newFramebuffer(texture textureNative, colorSpace graphicsdriver.ColorSpace) (framebufferNative, error) {
+	// If colorSpace is ColorSpaceLinear but isSRGBFramebufferSupported reports false, the caller
+	// is expected to use a shader-side pow(x, 2.2)/pow(x, 1/2.2) decode-encode pair instead of
+	// relying on fixed-function sRGB blending (glsl.Compile injects only the decode; the encode is
+	// glsl.WrapManualSRGBEncode); the framebuffer itself is created the same way either way.
 	f := c.ctx.CreateFramebuffer()
 	if f <= 0 {
 		return 0, fmt.Errorf("opengl: creating framebuffer failed: the returned value is not positive but %d", f)
@@ -352,6 +635,11 @@ func (c *context) newFramebuffer(texture textureNative) (framebufferNative, erro
 		}
 		return 0, fmt.Errorf("opengl: creating framebuffer failed: unknown error")
 	}
+
+	// Remember colorSpace so setViewport can toggle GL_FRAMEBUFFER_SRGB to match whichever
+	// framebuffer ends up bound; the flag is global GL state, not per-framebuffer-object.
+	c.framebufferColorSpaces[framebufferNative(f)] = colorSpace
+
 	return framebufferNative(f), nil
 }
 
@@ -382,6 +670,7 @@ func (c *context) deleteFramebuffer(f framebufferNative) {
 		c.lastViewportHeight = 0
 	}
 	c.ctx.DeleteFramebuffer(uint32(f))
+	delete(c.framebufferColorSpaces, f)
 }
 
 func (c *context) newVertexShader(source string) (shader, error) {
@@ -392,6 +681,62 @@ func (c *context) newFragmentShader(source string) (shader, error) {
 	return c.newShader(gl.FRAGMENT_SHADER, source)
 }
 
+// computeSupportedForVersion reports whether a GL(ES) version of major.minor supports compute
+// shaders: desktop OpenGL 4.3+ or OpenGL ES 3.1+. GLES2 and WebGL1, which Ebitengine still
+// targets, have no compute stage at all. This is split out from isComputeSupportedImpl so the
+// version-gating logic can be unit tested without a live GL context.
+func computeSupportedForVersion(isES bool, major, minor int) bool {
+	if isES {
+		return major > 3 || (major == 3 && minor >= 1)
+	}
+	return major > 4 || (major == 4 && minor >= 3)
+}
+
+// isComputeSupported reports whether the driver can run compute shaders. Desktop GL exposes
+// compute shaders starting at 4.3; GLES2 and WebGL1, which Ebitengine still targets, have no
+// compute stage at all.
+func (c *context) isComputeSupported() bool {
+	c.computeSupportedOnce.Do(func() {
+		c.computeSupported = c.isComputeSupportedImpl()
+	})
+	return c.computeSupported
+}
+
+// newComputeShader compiles a GLSL compute shader produced by the shaderir GLSL emitter for a
+// shaderir.ComputeProgram.
+func (c *context) newComputeShader(source string) (shader, error) {
+	if !c.isComputeSupported() {
+		return 0, errors.New("opengl: compute shaders are not supported on this driver")
+	}
+	return c.newShader(gl.COMPUTE_SHADER, source)
+}
+
+// newComputeShaderFromProgram lowers p to GLSL and compiles it, so that newComputeShader is
+// reachable from an actual shaderir.ComputeProgram rather than only from a hand-written source
+// string.
+func (c *context) newComputeShaderFromProgram(p *shaderir.ComputeProgram) (shader, error) {
+	return c.newComputeShader(glsl.CompileCompute(p))
+}
+
+// dispatchCompute dispatches gx * gy * gz local workgroups of the currently bound compute
+// program, mirroring the draw path's drawElements in that it assumes the caller has already bound
+// the program and any image/storage-buffer bindings the shader expects.
+func (c *context) dispatchCompute(gx, gy, gz int) error {
+	if !c.isComputeSupported() {
+		return errors.New("opengl: compute shaders are not supported on this driver")
+	}
+	c.ctx.DispatchCompute(uint32(gx), uint32(gy), uint32(gz))
+	return nil
+}
+
+// memoryBarrier inserts a memory barrier so that subsequent commands can safely observe the
+// effects of imageStore writes issued by a prior dispatchCompute call. mask is a bitwise OR of
+// GL_*_BARRIER_BIT constants, e.g. gl.SHADER_IMAGE_ACCESS_BARRIER_BIT for imageLoad/imageStore
+// or gl.FRAMEBUFFER_BARRIER_BIT before sampling the result as a regular texture.
+func (c *context) memoryBarrier(mask uint32) {
+	c.ctx.MemoryBarrier(mask)
+}
+
 func (c *context) newShader(shaderType uint32, source string) (shader, error) {
 	s := c.ctx.CreateShader(shaderType)
 	if s == 0 {
@@ -585,3 +930,33 @@ func (c *context) endStencilWithEvenOddRule() {
 func (c *context) isES() bool {
 	return c.ctx.IsES()
 }
+
+// timerQuery is a GL_TIME_ELAPSED query object used to measure how long a pass took on the GPU.
+type timerQuery uint32
+
+// newTimerQuery creates a timer query, for use by internal/debug to report real per-pass GPU
+// timings instead of only CPU frame times. It is only valid to call this when
+// isTimestampQueriesSupported reports true.
+func (c *context) newTimerQuery() timerQuery {
+	return timerQuery(c.ctx.GenQuery())
+}
+
+func (c *context) deleteTimerQuery(q timerQuery) {
+	c.ctx.DeleteQuery(uint32(q))
+}
+
+func (c *context) beginTimerQuery(q timerQuery) {
+	c.ctx.BeginQuery(gl.TIME_ELAPSED, uint32(q))
+}
+
+func (c *context) endTimerQuery() {
+	c.ctx.EndQuery(gl.TIME_ELAPSED)
+}
+
+// timerQueryResultNanoseconds returns q's elapsed time in nanoseconds. The caller must ensure the
+// query's result is already available, e.g. by checking GetQueryObjectuiv(q, GL_QUERY_RESULT_AVAILABLE)
+// or simply waiting a frame, since glGetQueryObjectui64v otherwise stalls the pipeline exactly
+// like the synchronous glReadPixels this package also tries to avoid.
+func (c *context) timerQueryResultNanoseconds(q timerQuery) uint64 {
+	return c.ctx.GetQueryObjectui64v(uint32(q))
+}
@@ -28,3 +28,73 @@ func (*context) stencilFormat() uint32 {
 	// https://www.khronos.org/opengl/wiki/Image_Format
 	return gl.DEPTH24_STENCIL8
 }
+
+func (c *context) srgbFramebufferSupportedImpl() bool {
+	// GL_FRAMEBUFFER_SRGB and GL_ARB_framebuffer_sRGB are core since OpenGL 3.0 on desktop.
+	// GLES2 has no equivalent, so callers on that driver must use the shader-side fallback.
+	if c.ctx.IsES() {
+		return false
+	}
+	return true
+}
+
+func (c *context) isComputeSupportedImpl() bool {
+	if c.ctx.IsES() {
+		major, minor := c.ctx.GetGLESVersion()
+		return computeSupportedForVersion(true, major, minor)
+	}
+	major, minor := c.ctx.GetGLVersion()
+	return computeSupportedForVersion(false, major, minor)
+}
+
+func (c *context) arbSyncSupportedImpl() bool {
+	// ARB_sync is core since OpenGL 3.2. This is only called when IsES() is already false.
+	major, minor := c.ctx.GetGLVersion()
+	return major > 3 || (major == 3 && minor >= 2)
+}
+
+func (c *context) fenceSyncImpl() glSync {
+	return glSync(c.ctx.FenceSync(gl.SYNC_GPU_COMMANDS_COMPLETE))
+}
+
+func (c *context) clientWaitSyncImpl(sync glSync, wait bool) bool {
+	var timeout uint64
+	if wait {
+		timeout = gl.TIMEOUT_IGNORED
+	}
+	status := c.ctx.ClientWaitSync(uintptr(sync), gl.SYNC_FLUSH_COMMANDS_BIT, timeout)
+	return status == gl.ALREADY_SIGNALED || status == gl.CONDITION_SATISFIED
+}
+
+func (c *context) mapBufferRangeImpl(target uint32, size int) []byte {
+	return c.ctx.MapBufferRange(target, 0, int32(size), gl.MAP_READ_BIT)
+}
+
+func (c *context) unmapBufferImpl(target uint32) {
+	c.ctx.UnmapBuffer(target)
+}
+
+func (c *context) deleteSyncImpl(sync glSync) {
+	c.ctx.DeleteSync(uintptr(sync))
+}
+
+func (c *context) srgbTextureSupportedImpl() bool {
+	// GL_SRGB8_ALPHA8 as a texture internal format is core since OpenGL 2.1 on desktop. GLES2 has
+	// no equivalent (it only gained it via the unextended GL_EXT_sRGB texture extension, which
+	// isn't guaranteed), so callers on that driver must use the shader-side fallback instead.
+	if c.ctx.IsES() {
+		return false
+	}
+	return true
+}
+
+func (c *context) timestampQueriesSupportedImpl() bool {
+	// GL_TIME_ELAPSED queries require desktop OpenGL 3.3+ (ARB_timer_query) or the
+	// GL_EXT_disjoint_timer_query extension on ES, which isn't guaranteed, so timestamp queries
+	// are only advertised as supported on desktop GL here.
+	if c.ctx.IsES() {
+		return false
+	}
+	major, minor := c.ctx.GetGLVersion()
+	return major > 3 || (major == 3 && minor >= 3)
+}
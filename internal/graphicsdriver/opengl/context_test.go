@@ -0,0 +1,97 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import "testing"
+
+func TestComputeSupportedForVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		isES  bool
+		major int
+		minor int
+		want  bool
+	}{
+		{name: "ES 3.0 is too old", isES: true, major: 3, minor: 0, want: false},
+		{name: "ES 3.1 is supported", isES: true, major: 3, minor: 1, want: true},
+		{name: "ES 3.2 is supported", isES: true, major: 3, minor: 2, want: true},
+		{name: "GLES2 is not supported", isES: true, major: 2, minor: 0, want: false},
+		{name: "desktop GL 4.2 is too old", isES: false, major: 4, minor: 2, want: false},
+		{name: "desktop GL 4.3 is supported", isES: false, major: 4, minor: 3, want: true},
+		{name: "desktop GL 4.6 is supported", isES: false, major: 4, minor: 6, want: true},
+		{name: "desktop GL 3.3 is not supported", isES: false, major: 3, minor: 3, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computeSupportedForVersion(tt.isES, tt.major, tt.minor); got != tt.want {
+				t.Errorf("computeSupportedForVersion(%v, %d, %d): got %v, want %v", tt.isES, tt.major, tt.minor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickPixelBufferRingSlot(t *testing.T) {
+	tests := []struct {
+		name             string
+		pending          []bool
+		sizes            []int
+		size             int
+		wantIndex        int
+		wantNeedsRealloc bool
+		wantMustBlock    bool
+	}{
+		{
+			name:      "first slot free and already the right size",
+			pending:   []bool{false, false, false},
+			sizes:     []int{64, 64, 64},
+			size:      64,
+			wantIndex: 0,
+		},
+		{
+			name:             "first slot free but the wrong size",
+			pending:          []bool{false, false, false},
+			sizes:            []int{32, 64, 64},
+			size:             64,
+			wantIndex:        0,
+			wantNeedsRealloc: true,
+		},
+		{
+			name:      "first slot pending, second free",
+			pending:   []bool{true, false, false},
+			sizes:     []int{64, 64, 64},
+			size:      64,
+			wantIndex: 1,
+		},
+		{
+			name:          "every slot pending",
+			pending:       []bool{true, true, true},
+			sizes:         []int{64, 64, 64},
+			size:          64,
+			wantIndex:     0,
+			wantMustBlock: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, needsRealloc, mustBlock := pickPixelBufferRingSlot(tt.pending, tt.sizes, tt.size)
+			if index != tt.wantIndex || needsRealloc != tt.wantNeedsRealloc || mustBlock != tt.wantMustBlock {
+				t.Errorf("pickPixelBufferRingSlot(%v, %v, %d): got (%d, %v, %v), want (%d, %v, %v)",
+					tt.pending, tt.sizes, tt.size, index, needsRealloc, mustBlock, tt.wantIndex, tt.wantNeedsRealloc, tt.wantMustBlock)
+			}
+		})
+	}
+}
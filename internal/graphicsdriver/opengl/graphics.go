@@ -0,0 +1,50 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opengl
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver"
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/opengl/gl"
+)
+
+// Graphics is an OpenGL implementation of graphicsdriver.Graphics.
+type Graphics struct {
+	context context
+}
+
+// NewGraphics creates an OpenGL-backed graphicsdriver.Graphics using the platform's default GL
+// context. OpenGL is supported on every platform Ebitengine targets, so unlike Vulkan this
+// constructor has no build tag of its own and is always registered.
+func NewGraphics() (*Graphics, error) {
+	ctx, err := gl.NewDefaultContext()
+	if err != nil {
+		return nil, fmt.Errorf("opengl: creating a default context failed: %w", err)
+	}
+	return &Graphics{context: context{ctx: ctx}}, nil
+}
+
+// Capabilities reports what this OpenGL backend supports, for graphicsdriver.Auto to compare
+// against the other registered backends.
+func (g *Graphics) Capabilities() graphicsdriver.Capabilities {
+	return g.context.capabilities()
+}
+
+func init() {
+	graphicsdriver.RegisterBackend("opengl", func() (graphicsdriver.Graphics, error) {
+		return NewGraphics()
+	})
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !vulkan
+
+// Package vulkan provides a Vulkan implementation of the graphicsdriver.Graphics interface.
+//
+// This build does not have the vulkan build tag set, so NewGraphics always fails. Ebitengine
+// falls back to another backend in this case.
+package vulkan
+
+import (
+	"errors"
+)
+
+// Graphics is an unusable stand-in for the Vulkan-backed graphicsdriver.Graphics. It exists so
+// that packages can reference vulkan.Graphics and vulkan.NewGraphics without needing a build tag
+// of their own.
+type Graphics struct{}
+
+// NewGraphics always returns an error, as this binary was built without the vulkan build tag.
+func NewGraphics() (*Graphics, error) {
+	return nil, errors.New("vulkan: Vulkan is not supported in this build (rebuild with -tags vulkan)")
+}
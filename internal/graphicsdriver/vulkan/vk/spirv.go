@@ -0,0 +1,72 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build vulkan
+
+package vk
+
+/*
+#cgo LDFLAGS: -lshaderc_shared
+#include <shaderc/shaderc.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// CompileGLSLToSPIRV compiles GLSL source to SPIR-V bytecode for the given stage, using
+// libshaderc. This is a separate step from Vulkan object creation proper: Vulkan itself only
+// consumes SPIR-V, so the GLSL the shaderir glsl emitter produces for Vulkan targets must always
+// go through a GLSL-to-SPIR-V compiler before CreateShaderModule can use it.
+func CompileGLSLToSPIRV(source string, stage ShaderStage) ([]byte, error) {
+	compiler := C.shaderc_compiler_initialize()
+	if compiler == nil {
+		return nil, fmt.Errorf("vk: shaderc_compiler_initialize failed")
+	}
+	defer C.shaderc_compiler_release(compiler)
+
+	var kind C.shaderc_shader_kind
+	switch stage {
+	case ShaderStageVertex:
+		kind = C.shaderc_vertex_shader
+	case ShaderStageFragment:
+		kind = C.shaderc_fragment_shader
+	case ShaderStageCompute:
+		kind = C.shaderc_compute_shader
+	default:
+		return nil, fmt.Errorf("vk: invalid shader stage %d", stage)
+	}
+
+	cSource := C.CString(source)
+	defer C.free(unsafe.Pointer(cSource))
+	cName := C.CString("ebiten-shader")
+	defer C.free(unsafe.Pointer(cName))
+	cEntry := C.CString("main")
+	defer C.free(unsafe.Pointer(cEntry))
+
+	result := C.shaderc_compile_into_spv(compiler, cSource, C.size_t(len(source)), kind, cName, cEntry, nil)
+	defer C.shaderc_result_release(result)
+
+	if status := C.shaderc_result_get_compilation_status(result); status != C.shaderc_compilation_status_success {
+		msg := C.GoString(C.shaderc_result_get_error_message(result))
+		return nil, fmt.Errorf("vk: compiling GLSL to SPIR-V failed: %s", msg)
+	}
+
+	n := C.shaderc_result_get_length(result)
+	bytes := C.GoBytes(unsafe.Pointer(C.shaderc_result_get_bytes(result)), C.int(n))
+	return bytes, nil
+}
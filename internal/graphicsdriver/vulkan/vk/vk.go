@@ -0,0 +1,431 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build vulkan
+
+// Package vk is a minimal cgo binding to the system Vulkan loader, exposing only the subset of
+// the API the vulkan package's graphicsdriver.Graphics implementation needs. It deliberately does
+// not attempt to be a general-purpose Vulkan binding.
+package vk
+
+/*
+#cgo LDFLAGS: -lvulkan
+#include <vulkan/vulkan.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// Format mirrors VkFormat values relevant to Ebitengine's stencil and texture usage.
+type Format int32
+
+const (
+	FormatD24UnormS8Uint  Format = C.VK_FORMAT_D24_UNORM_S8_UINT
+	FormatD32SfloatS8Uint Format = C.VK_FORMAT_D32_SFLOAT_S8_UINT
+	FormatS8Uint          Format = C.VK_FORMAT_S8_UINT
+)
+
+// FormatFeature mirrors the VkFormatFeatureFlagBits this package checks for.
+type FormatFeature uint32
+
+const (
+	FormatFeatureDepthStencilAttachment FormatFeature = C.VK_FORMAT_FEATURE_DEPTH_STENCIL_ATTACHMENT_BIT
+)
+
+// PresentMode mirrors VkPresentModeKHR.
+type PresentMode int32
+
+const (
+	PresentModeFIFO    PresentMode = C.VK_PRESENT_MODE_FIFO_KHR
+	PresentModeMailbox PresentMode = C.VK_PRESENT_MODE_MAILBOX_KHR
+)
+
+// PhysicalDeviceType mirrors VkPhysicalDeviceType.
+type PhysicalDeviceType int32
+
+const (
+	PhysicalDeviceTypeOther         PhysicalDeviceType = C.VK_PHYSICAL_DEVICE_TYPE_OTHER
+	PhysicalDeviceTypeIntegratedGPU PhysicalDeviceType = C.VK_PHYSICAL_DEVICE_TYPE_INTEGRATED_GPU
+	PhysicalDeviceTypeDiscreteGPU   PhysicalDeviceType = C.VK_PHYSICAL_DEVICE_TYPE_DISCRETE_GPU
+	PhysicalDeviceTypeVirtualGPU    PhysicalDeviceType = C.VK_PHYSICAL_DEVICE_TYPE_VIRTUAL_GPU
+	PhysicalDeviceTypeCPU           PhysicalDeviceType = C.VK_PHYSICAL_DEVICE_TYPE_CPU
+)
+
+// ShaderStage selects which stage CompileGLSLToSPIRV compiles source for.
+type ShaderStage int
+
+const (
+	ShaderStageVertex ShaderStage = iota
+	ShaderStageFragment
+	ShaderStageCompute
+)
+
+// PhysicalDeviceLimits is the plain-Go subset of VkPhysicalDeviceLimits that
+// graphicsdriver.Capabilities is built from.
+type PhysicalDeviceLimits struct {
+	MaxImageDimension2D                uint32
+	MaxPerStageDescriptorSampledImages uint32
+	FramebufferColorSampleCounts       int
+	MinUniformBufferOffsetAlignment    uint64
+	TimestampComputeAndGraphics        bool
+}
+
+// PhysicalDeviceProperties is the plain-Go subset of VkPhysicalDeviceProperties this package
+// reads. It is a value type rather than a cgo handle so that device-selection logic built on top
+// of it can be unit tested with synthetic values.
+type PhysicalDeviceProperties struct {
+	DeviceType PhysicalDeviceType
+	Limits     PhysicalDeviceLimits
+}
+
+// FormatProperties is the plain-Go subset of VkFormatProperties this package reads.
+type FormatProperties struct {
+	OptimalTilingFeatures FormatFeature
+}
+
+// Instance wraps a VkInstance.
+type Instance struct {
+	handle C.VkInstance
+}
+
+// PhysicalDevice wraps a VkPhysicalDevice. Like the other handle wrappers here, it is comparable
+// and has a usable zero value; choosePhysicalDevice returns it by value.
+type PhysicalDevice struct {
+	handle C.VkPhysicalDevice
+}
+
+// Device wraps a VkDevice.
+type Device struct {
+	handle C.VkDevice
+}
+
+// Queue wraps a VkQueue.
+type Queue struct {
+	handle C.VkQueue
+}
+
+// Swapchain wraps a VkSwapchainKHR.
+type Swapchain struct {
+	device C.VkDevice
+	handle C.VkSwapchainKHR
+}
+
+// Surface wraps a VkSurfaceKHR. Creating the underlying handle is platform-specific (Xlib/
+// Wayland/Win32 each have their own vkCreate*SurfaceKHR entry point), and this package has no
+// function that creates a Surface or wraps an existing handle, so outside this package only the
+// zero Surface can be made; see Graphics.SetSurface.
+type Surface struct {
+	handle C.VkSurfaceKHR
+}
+
+// SurfaceCapabilities is the plain-Go subset of VkSurfaceCapabilitiesKHR needed to populate a
+// swapchain's preTransform.
+type SurfaceCapabilities struct {
+	CurrentTransform uint32 // a VkSurfaceTransformFlagBitsKHR value
+}
+
+// CommandPool wraps a VkCommandPool.
+type CommandPool struct {
+	device C.VkDevice
+	handle C.VkCommandPool
+}
+
+// CommandBuffer wraps a VkCommandBuffer.
+type CommandBuffer struct {
+	handle C.VkCommandBuffer
+}
+
+// ShaderModule wraps a VkShaderModule.
+type ShaderModule struct {
+	device C.VkDevice
+	handle C.VkShaderModule
+}
+
+// CreateInstance creates a VkInstance with the extensions Ebitengine's swapchain path needs.
+func CreateInstance() (Instance, error) {
+	appName := C.CString("ebiten")
+	defer C.free(unsafe.Pointer(appName))
+
+	appInfo := C.VkApplicationInfo{
+		sType:            C.VK_STRUCTURE_TYPE_APPLICATION_INFO,
+		pApplicationName: appName,
+		apiVersion:       C.VK_API_VERSION_1_1,
+	}
+	createInfo := C.VkInstanceCreateInfo{
+		sType:            C.VK_STRUCTURE_TYPE_INSTANCE_CREATE_INFO,
+		pApplicationInfo: &appInfo,
+	}
+
+	var instance C.VkInstance
+	if result := C.vkCreateInstance(&createInfo, nil, &instance); result != C.VK_SUCCESS {
+		return Instance{}, fmt.Errorf("vk: vkCreateInstance failed: %d", result)
+	}
+	return Instance{handle: instance}, nil
+}
+
+// EnumeratePhysicalDevices lists the physical devices visible to i.
+func (i Instance) EnumeratePhysicalDevices() ([]PhysicalDevice, error) {
+	var count C.uint32_t
+	if result := C.vkEnumeratePhysicalDevices(i.handle, &count, nil); result != C.VK_SUCCESS {
+		return nil, fmt.Errorf("vk: vkEnumeratePhysicalDevices (count) failed: %d", result)
+	}
+	if count == 0 {
+		return nil, errors.New("vk: no physical devices were enumerated")
+	}
+
+	handles := make([]C.VkPhysicalDevice, count)
+	if result := C.vkEnumeratePhysicalDevices(i.handle, &count, &handles[0]); result != C.VK_SUCCESS {
+		return nil, fmt.Errorf("vk: vkEnumeratePhysicalDevices failed: %d", result)
+	}
+
+	devices := make([]PhysicalDevice, len(handles))
+	for idx, h := range handles {
+		devices[idx] = PhysicalDevice{handle: h}
+	}
+	return devices, nil
+}
+
+// Properties returns pd's VkPhysicalDeviceProperties, translated into plain Go values.
+func (pd PhysicalDevice) Properties() PhysicalDeviceProperties {
+	var props C.VkPhysicalDeviceProperties
+	C.vkGetPhysicalDeviceProperties(pd.handle, &props)
+
+	return PhysicalDeviceProperties{
+		DeviceType: PhysicalDeviceType(props.deviceType),
+		Limits: PhysicalDeviceLimits{
+			MaxImageDimension2D:                uint32(props.limits.maxImageDimension2D),
+			MaxPerStageDescriptorSampledImages: uint32(props.limits.maxPerStageDescriptorSampledImages),
+			FramebufferColorSampleCounts:        int(props.limits.framebufferColorSampleCounts),
+			MinUniformBufferOffsetAlignment:     uint64(props.limits.minUniformBufferOffsetAlignment),
+			TimestampComputeAndGraphics:         props.limits.timestampComputeAndGraphics != 0,
+		},
+	}
+}
+
+// FormatProperties returns pd's VkFormatProperties for f.
+func (pd PhysicalDevice) FormatProperties(f Format) FormatProperties {
+	var props C.VkFormatProperties
+	C.vkGetPhysicalDeviceFormatProperties(pd.handle, C.VkFormat(f), &props)
+	return FormatProperties{
+		OptimalTilingFeatures: FormatFeature(props.optimalTilingFeatures),
+	}
+}
+
+// SurfacePresentModes lists the present modes surface supports on pd.
+func (pd PhysicalDevice) SurfacePresentModes(surface Surface) ([]PresentMode, error) {
+	var count C.uint32_t
+	if result := C.vkGetPhysicalDeviceSurfacePresentModesKHR(pd.handle, surface.handle, &count, nil); result != C.VK_SUCCESS {
+		return nil, fmt.Errorf("vk: vkGetPhysicalDeviceSurfacePresentModesKHR (count) failed: %d", result)
+	}
+	if count == 0 {
+		return []PresentMode{PresentModeFIFO}, nil
+	}
+
+	modes := make([]C.VkPresentModeKHR, count)
+	if result := C.vkGetPhysicalDeviceSurfacePresentModesKHR(pd.handle, surface.handle, &count, &modes[0]); result != C.VK_SUCCESS {
+		return nil, fmt.Errorf("vk: vkGetPhysicalDeviceSurfacePresentModesKHR failed: %d", result)
+	}
+
+	out := make([]PresentMode, len(modes))
+	for i, m := range modes {
+		out[i] = PresentMode(m)
+	}
+	return out, nil
+}
+
+// SurfaceFormat returns the image format surface should be created with on pd. Ebitengine always
+// requests the widely supported 8-bit BGRA format rather than enumerating
+// vkGetPhysicalDeviceSurfaceFormatsKHR's list, since every conformant presentable surface supports
+// it.
+func (pd PhysicalDevice) SurfaceFormat(surface Surface) Format {
+	return Format(C.VK_FORMAT_B8G8R8A8_UNORM)
+}
+
+// SurfaceCapabilities returns pd's VkSurfaceCapabilitiesKHR for surface.
+func (pd PhysicalDevice) SurfaceCapabilities(surface Surface) (SurfaceCapabilities, error) {
+	var caps C.VkSurfaceCapabilitiesKHR
+	if result := C.vkGetPhysicalDeviceSurfaceCapabilitiesKHR(pd.handle, surface.handle, &caps); result != C.VK_SUCCESS {
+		return SurfaceCapabilities{}, fmt.Errorf("vk: vkGetPhysicalDeviceSurfaceCapabilitiesKHR failed: %d", result)
+	}
+	return SurfaceCapabilities{CurrentTransform: uint32(caps.currentTransform)}, nil
+}
+
+// CreateLogicalDevice creates a VkDevice and retrieves its single graphics/present queue.
+func (pd PhysicalDevice) CreateLogicalDevice() (Device, Queue, error) {
+	queuePriority := C.float(1.0)
+	queueCreateInfo := C.VkDeviceQueueCreateInfo{
+		sType:            C.VK_STRUCTURE_TYPE_DEVICE_QUEUE_CREATE_INFO,
+		queueFamilyIndex: 0,
+		queueCount:       1,
+		pQueuePriorities: &queuePriority,
+	}
+	createInfo := C.VkDeviceCreateInfo{
+		sType:                C.VK_STRUCTURE_TYPE_DEVICE_CREATE_INFO,
+		queueCreateInfoCount: 1,
+		pQueueCreateInfos:    &queueCreateInfo,
+	}
+
+	var device C.VkDevice
+	if result := C.vkCreateDevice(pd.handle, &createInfo, nil, &device); result != C.VK_SUCCESS {
+		return Device{}, Queue{}, fmt.Errorf("vk: vkCreateDevice failed: %d", result)
+	}
+
+	var queue C.VkQueue
+	C.vkGetDeviceQueue(device, 0, 0, &queue)
+
+	return Device{handle: device}, Queue{handle: queue}, nil
+}
+
+// CreateSwapchain creates a VkSwapchainKHR for surface at the given size, format, and present
+// mode, optionally reusing old as VkSwapchainCreateInfoKHR.oldSwapchain so the driver can hand
+// resources off directly instead of tearing the previous swapchain down first. preTransform
+// should be the surface's current SurfaceCapabilities.CurrentTransform, since most presentation
+// engines require the swapchain to match it exactly rather than applying rotation itself.
+func (d Device) CreateSwapchain(surface Surface, width, height int, format Format, preTransform uint32, mode PresentMode, old Swapchain) (Swapchain, error) {
+	createInfo := C.VkSwapchainCreateInfoKHR{
+		sType:            C.VK_STRUCTURE_TYPE_SWAPCHAIN_CREATE_INFO_KHR,
+		surface:          surface.handle,
+		minImageCount:    2,
+		imageFormat:      C.VkFormat(format),
+		imageColorSpace:  C.VK_COLOR_SPACE_SRGB_NONLINEAR_KHR,
+		imageExtent:      C.VkExtent2D{width: C.uint32_t(width), height: C.uint32_t(height)},
+		imageArrayLayers: 1,
+		imageUsage:       C.VK_IMAGE_USAGE_COLOR_ATTACHMENT_BIT,
+		imageSharingMode: C.VK_SHARING_MODE_EXCLUSIVE,
+		preTransform:     C.VkSurfaceTransformFlagBitsKHR(preTransform),
+		compositeAlpha:   C.VK_COMPOSITE_ALPHA_OPAQUE_BIT_KHR,
+		presentMode:      C.VkPresentModeKHR(mode),
+		oldSwapchain:     old.handle,
+	}
+
+	var swapchain C.VkSwapchainKHR
+	if result := C.vkCreateSwapchainKHR(d.handle, &createInfo, nil, &swapchain); result != C.VK_SUCCESS {
+		return Swapchain{}, fmt.Errorf("vk: vkCreateSwapchainKHR failed: %d", result)
+	}
+	return Swapchain{device: d.handle, handle: swapchain}, nil
+}
+
+// CreateCommandPool creates a VkCommandPool for queue family 0, the single graphics/present queue
+// family CreateLogicalDevice requests. VK_COMMAND_POOL_CREATE_RESET_COMMAND_BUFFER_BIT is set so
+// individual command buffers allocated from it can be reset and re-recorded per frame instead of
+// requiring the whole pool to be reset at once.
+func (d Device) CreateCommandPool() (CommandPool, error) {
+	createInfo := C.VkCommandPoolCreateInfo{
+		sType:            C.VK_STRUCTURE_TYPE_COMMAND_POOL_CREATE_INFO,
+		flags:            C.VK_COMMAND_POOL_CREATE_RESET_COMMAND_BUFFER_BIT,
+		queueFamilyIndex: 0,
+	}
+
+	var pool C.VkCommandPool
+	if result := C.vkCreateCommandPool(d.handle, &createInfo, nil, &pool); result != C.VK_SUCCESS {
+		return CommandPool{}, fmt.Errorf("vk: vkCreateCommandPool failed: %d", result)
+	}
+	return CommandPool{device: d.handle, handle: pool}, nil
+}
+
+// AllocateCommandBuffer allocates a single primary VkCommandBuffer from p.
+func (p CommandPool) AllocateCommandBuffer() (CommandBuffer, error) {
+	allocInfo := C.VkCommandBufferAllocateInfo{
+		sType:              C.VK_STRUCTURE_TYPE_COMMAND_BUFFER_ALLOCATE_INFO,
+		commandPool:        p.handle,
+		level:              C.VK_COMMAND_BUFFER_LEVEL_PRIMARY,
+		commandBufferCount: 1,
+	}
+
+	var cb C.VkCommandBuffer
+	if result := C.vkAllocateCommandBuffers(p.device, &allocInfo, &cb); result != C.VK_SUCCESS {
+		return CommandBuffer{}, fmt.Errorf("vk: vkAllocateCommandBuffers failed: %d", result)
+	}
+	return CommandBuffer{handle: cb}, nil
+}
+
+// Destroy destroys p's underlying VkCommandPool, along with every command buffer allocated from it.
+func (p CommandPool) Destroy() {
+	C.vkDestroyCommandPool(p.device, p.handle, nil)
+}
+
+// Begin starts recording into cb, resetting any commands it previously held.
+func (cb CommandBuffer) Begin() error {
+	beginInfo := C.VkCommandBufferBeginInfo{
+		sType: C.VK_STRUCTURE_TYPE_COMMAND_BUFFER_BEGIN_INFO,
+	}
+	if result := C.vkBeginCommandBuffer(cb.handle, &beginInfo); result != C.VK_SUCCESS {
+		return fmt.Errorf("vk: vkBeginCommandBuffer failed: %d", result)
+	}
+	return nil
+}
+
+// End finishes recording into cb, making it submittable to a queue.
+func (cb CommandBuffer) End() error {
+	if result := C.vkEndCommandBuffer(cb.handle); result != C.VK_SUCCESS {
+		return fmt.Errorf("vk: vkEndCommandBuffer failed: %d", result)
+	}
+	return nil
+}
+
+// CreateShaderModule creates a VkShaderModule from SPIR-V bytecode.
+func (d Device) CreateShaderModule(spirv []byte) (ShaderModule, error) {
+	if len(spirv) == 0 || len(spirv)%4 != 0 {
+		return ShaderModule{}, fmt.Errorf("vk: SPIR-V bytecode length must be a positive multiple of 4, got %d", len(spirv))
+	}
+
+	createInfo := C.VkShaderModuleCreateInfo{
+		sType:    C.VK_STRUCTURE_TYPE_SHADER_MODULE_CREATE_INFO,
+		codeSize: C.size_t(len(spirv)),
+		pCode:    (*C.uint32_t)(unsafe.Pointer(&spirv[0])),
+	}
+
+	var module C.VkShaderModule
+	if result := C.vkCreateShaderModule(d.handle, &createInfo, nil, &module); result != C.VK_SUCCESS {
+		return ShaderModule{}, fmt.Errorf("vk: vkCreateShaderModule failed: %d", result)
+	}
+	return ShaderModule{device: d.handle, handle: module}, nil
+}
+
+// Destroy destroys s's underlying VkSwapchainKHR.
+func (s Swapchain) Destroy() {
+	C.vkDestroySwapchainKHR(s.device, s.handle, nil)
+}
+
+// Destroy destroys m's underlying VkShaderModule.
+func (m ShaderModule) Destroy() {
+	C.vkDestroyShaderModule(m.device, m.handle, nil)
+}
+
+// SetScissor records vkCmdSetScissor into cb.
+func (cb CommandBuffer) SetScissor(x, y, width, height int) {
+	rect := C.VkRect2D{
+		offset: C.VkOffset2D{x: C.int32_t(x), y: C.int32_t(y)},
+		extent: C.VkExtent2D{width: C.uint32_t(width), height: C.uint32_t(height)},
+	}
+	C.vkCmdSetScissor(cb.handle, 0, 1, &rect)
+}
+
+// SetViewport records vkCmdSetViewport into cb.
+func (cb CommandBuffer) SetViewport(x, y, width, height float32) {
+	viewport := C.VkViewport{
+		x:        C.float(x),
+		y:        C.float(y),
+		width:    C.float(width),
+		height:   C.float(height),
+		minDepth: 0,
+		maxDepth: 1,
+	}
+	C.vkCmdSetViewport(cb.handle, 0, 1, &viewport)
+}
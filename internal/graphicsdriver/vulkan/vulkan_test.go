@@ -0,0 +1,125 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build vulkan
+
+package vulkan
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/graphicsdriver/vulkan/vk"
+)
+
+func TestChoosePhysicalDeviceIndexPrefersDiscreteGPU(t *testing.T) {
+	props := []vk.PhysicalDeviceProperties{
+		{
+			DeviceType: vk.PhysicalDeviceTypeIntegratedGPU,
+			Limits:     vk.PhysicalDeviceLimits{MaxImageDimension2D: 16384},
+		},
+		{
+			DeviceType: vk.PhysicalDeviceTypeDiscreteGPU,
+			Limits:     vk.PhysicalDeviceLimits{MaxImageDimension2D: 8192},
+		},
+	}
+
+	idx, err := choosePhysicalDeviceIndex(props)
+	if err != nil {
+		t.Fatalf("choosePhysicalDeviceIndex returned an error: %v", err)
+	}
+	if got, want := idx, 1; got != want {
+		t.Errorf("choosePhysicalDeviceIndex: got %d, want %d", got, want)
+	}
+}
+
+func TestChoosePhysicalDeviceIndexBreaksTiesByMaxImageDimension(t *testing.T) {
+	props := []vk.PhysicalDeviceProperties{
+		{
+			DeviceType: vk.PhysicalDeviceTypeDiscreteGPU,
+			Limits:     vk.PhysicalDeviceLimits{MaxImageDimension2D: 8192},
+		},
+		{
+			DeviceType: vk.PhysicalDeviceTypeDiscreteGPU,
+			Limits:     vk.PhysicalDeviceLimits{MaxImageDimension2D: 16384},
+		},
+	}
+
+	idx, err := choosePhysicalDeviceIndex(props)
+	if err != nil {
+		t.Fatalf("choosePhysicalDeviceIndex returned an error: %v", err)
+	}
+	if got, want := idx, 1; got != want {
+		t.Errorf("choosePhysicalDeviceIndex: got %d, want %d", got, want)
+	}
+}
+
+func TestChoosePhysicalDeviceIndexNoDevices(t *testing.T) {
+	if _, err := choosePhysicalDeviceIndex(nil); err == nil {
+		t.Error("choosePhysicalDeviceIndex with no devices: got nil error, want non-nil")
+	}
+}
+
+func TestPresentMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		modes        []vk.PresentMode
+		vsyncEnabled bool
+		want         vk.PresentMode
+	}{
+		{
+			name:         "vsync off prefers mailbox",
+			modes:        []vk.PresentMode{vk.PresentModeFIFO, vk.PresentModeMailbox},
+			vsyncEnabled: false,
+			want:         vk.PresentModeMailbox,
+		},
+		{
+			name:         "vsync off falls back to fifo without mailbox",
+			modes:        []vk.PresentMode{vk.PresentModeFIFO},
+			vsyncEnabled: false,
+			want:         vk.PresentModeFIFO,
+		},
+		{
+			name:         "vsync on always uses fifo",
+			modes:        []vk.PresentMode{vk.PresentModeFIFO, vk.PresentModeMailbox},
+			vsyncEnabled: true,
+			want:         vk.PresentModeFIFO,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := presentMode(tt.modes, tt.vsyncEnabled); got != tt.want {
+				t.Errorf("presentMode: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChooseStencilFormat(t *testing.T) {
+	f, err := chooseStencilFormat(func(f vk.Format) bool {
+		return f == vk.FormatD32SfloatS8Uint
+	})
+	if err != nil {
+		t.Fatalf("chooseStencilFormat returned an error: %v", err)
+	}
+	if got, want := f, vk.FormatD32SfloatS8Uint; got != want {
+		t.Errorf("chooseStencilFormat: got %v, want %v", got, want)
+	}
+}
+
+func TestChooseStencilFormatNoneSupported(t *testing.T) {
+	if _, err := chooseStencilFormat(func(vk.Format) bool { return false }); err == nil {
+		t.Error("chooseStencilFormat with nothing supported: got nil error, want non-nil")
+	}
+}
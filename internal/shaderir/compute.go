@@ -0,0 +1,64 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shaderir
+
+// ComputeIntrinsic identifies one of the image intrinsics a Compute stage body may call.
+type ComputeIntrinsic int
+
+const (
+	ImageLoad ComputeIntrinsic = iota
+	ImageStore
+)
+
+// StorageImageFormat is a GLSL image format layout qualifier, e.g. "rgba8" for
+// `layout(rgba8) uniform image2D`.
+type StorageImageFormat string
+
+const (
+	StorageImageFormatRGBA8   StorageImageFormat = "rgba8"
+	StorageImageFormatRGBA32F StorageImageFormat = "rgba32f"
+	StorageImageFormatR32F    StorageImageFormat = "r32f"
+)
+
+// StorageImageBinding describes one storage image bound to a ComputeProgram, matching a
+// `layout(binding = N, format) uniform image2D` declaration in the emitted GLSL.
+type StorageImageBinding struct {
+	Binding int
+	Format  StorageImageFormat
+	// ReadOnly and WriteOnly mirror the GLSL `readonly`/`writeonly` image qualifiers; a binding
+	// that imageLoad reads from but never imageStore writes to (or vice versa) should set the
+	// matching flag so the emitter can add the qualifier and drivers can assume the narrower
+	// access pattern.
+	ReadOnly  bool
+	WriteOnly bool
+}
+
+// ComputeProgram is the IR for a Kage shader's Compute stage: a local workgroup size, the storage
+// images its body reads and writes via ImageLoad/ImageStore, and the body itself.
+//
+// Body is GLSL source text, not a structured statement/expression tree (Program's FragmentBody and
+// VertexBody carry GLSL text the same way, for the same reason: Kage doesn't lower to a shared IR
+// node type anywhere in this package yet). A GLSL-only Body is a real ceiling, not a to-do: an MSL
+// or HLSL emitter has no way to translate GLSL text, so reaching those backends needs ComputeProgram
+// (and Program) rebuilt around a shared, language-independent representation of the body — a
+// larger restructuring than fits in a single change. CompileCompute is GLSL's emitter only; there
+// is no MSL/HLSL compute emitter in this package to keep in sync with it.
+type ComputeProgram struct {
+	WorkgroupSizeX int
+	WorkgroupSizeY int
+	WorkgroupSizeZ int
+	Images         []StorageImageBinding
+	Body           string
+}
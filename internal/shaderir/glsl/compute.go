@@ -0,0 +1,67 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glsl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+)
+
+// CompileCompute lowers a shaderir.ComputeProgram to a GLSL 4.30 compute shader source, the
+// minimum version exposing the compute stage and image load/store intrinsics on desktop GL.
+// There is no ES/WebGL equivalent to target here: GLES2 and WebGL1 have no compute stage at all,
+// so callers must check a capability probe (e.g. opengl.context.isComputeSupported) before ever
+// reaching this function.
+//
+// This is a GLSL-only emitter, and deliberately so: ComputeProgram.Body is already GLSL text (see
+// its doc comment), so there is nothing for an MSL or HLSL sibling of this function to translate
+// without ComputeProgram first growing a language-independent body representation.
+func CompileCompute(p *shaderir.ComputeProgram) string {
+	var buf strings.Builder
+
+	fmt.Fprintf(&buf, "#version 430\n\n")
+	fmt.Fprintf(&buf, "layout(local_size_x = %d, local_size_y = %d, local_size_z = %d) in;\n\n",
+		maxInt(p.WorkgroupSizeX, 1), maxInt(p.WorkgroupSizeY, 1), maxInt(p.WorkgroupSizeZ, 1))
+
+	for _, img := range p.Images {
+		qualifiers := []string{fmt.Sprintf("binding = %d", img.Binding), string(img.Format)}
+		access := ""
+		switch {
+		case img.ReadOnly:
+			access = "readonly "
+		case img.WriteOnly:
+			access = "writeonly "
+		}
+		fmt.Fprintf(&buf, "layout(%s) %suniform image2D image%d;\n", strings.Join(qualifiers, ", "), access, img.Binding)
+	}
+	if len(p.Images) > 0 {
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString("void main() {\n")
+	buf.WriteString(p.Body)
+	buf.WriteString("\n}\n")
+
+	return buf.String()
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
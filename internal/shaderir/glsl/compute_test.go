@@ -0,0 +1,55 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glsl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+)
+
+func TestCompileCompute(t *testing.T) {
+	src := CompileCompute(&shaderir.ComputeProgram{
+		WorkgroupSizeX: 8,
+		WorkgroupSizeY: 8,
+		WorkgroupSizeZ: 1,
+		Images: []shaderir.StorageImageBinding{
+			{Binding: 0, Format: shaderir.StorageImageFormatRGBA8, ReadOnly: true},
+			{Binding: 1, Format: shaderir.StorageImageFormatRGBA8, WriteOnly: true},
+		},
+		Body: "\tvec4 c = imageLoad(image0, ivec2(gl_GlobalInvocationID.xy));\n\timageStore(image1, ivec2(gl_GlobalInvocationID.xy), c);",
+	})
+
+	for _, want := range []string{
+		"#version 430",
+		"layout(local_size_x = 8, local_size_y = 8, local_size_z = 1) in;",
+		"layout(binding = 0, rgba8) readonly uniform image2D image0;",
+		"layout(binding = 1, rgba8) writeonly uniform image2D image1;",
+		"imageLoad(image0,",
+		"imageStore(image1,",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("CompileCompute output does not contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestCompileComputeDefaultsMissingWorkgroupDimensionsToOne(t *testing.T) {
+	src := CompileCompute(&shaderir.ComputeProgram{Body: ""})
+	if want := "layout(local_size_x = 1, local_size_y = 1, local_size_z = 1) in;"; !strings.Contains(src, want) {
+		t.Errorf("CompileCompute output does not contain %q:\n%s", want, src)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package glsl translates shaderir programs to GLSL source.
+package glsl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+)
+
+// GLSLVersion identifies the `#version` directive Compile emits, e.g. 450 for GLSLVersionVulkan.
+type GLSLVersion int
+
+// Compile lowers p to GLSL vertex and fragment shader sources targeting version. Wherever
+// p.FragmentBody contains shaderir.TextureSamplePlaceholder, it is replaced with
+// p.FragmentTextureSample — wrapped in the shader-side sRGB decode via WrapManualSRGBDecode when
+// p.FragmentNeedsManualSRGBDecode is set, so a driver without hardware sRGB texture support still
+// samples linear-space textures correctly.
+func Compile(p *shaderir.Program, version GLSLVersion) (vertexSrc, fragmentSrc string) {
+	sample := p.FragmentTextureSample
+	if p.FragmentNeedsManualSRGBDecode {
+		sample = WrapManualSRGBDecode(sample)
+	}
+	fragBody := strings.ReplaceAll(p.FragmentBody, shaderir.TextureSamplePlaceholder, sample)
+
+	vertexSrc = fmt.Sprintf("#version %d\n\nvoid main() {\n%s\n}\n", version, p.VertexBody)
+	fragmentSrc = fmt.Sprintf("#version %d\n\nvoid main() {\n%s\n}\n", version, fragBody)
+	return vertexSrc, fragmentSrc
+}
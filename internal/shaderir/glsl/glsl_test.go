@@ -0,0 +1,49 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glsl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2/internal/shaderir"
+)
+
+func TestCompileSamplesTextureDirectlyWhenSRGBSupported(t *testing.T) {
+	_, fragSrc := Compile(&shaderir.Program{
+		FragmentBody:          "\tgl_FragColor = " + shaderir.TextureSamplePlaceholder + ";",
+		FragmentTextureSample: "texture(tex, uv)",
+	}, 450)
+
+	if !strings.Contains(fragSrc, "gl_FragColor = texture(tex, uv);") {
+		t.Errorf("Compile output does not sample the texture directly:\n%s", fragSrc)
+	}
+	if strings.Contains(fragSrc, "pow(") {
+		t.Errorf("Compile output should not manually decode sRGB when not needed:\n%s", fragSrc)
+	}
+}
+
+func TestCompileWrapsTextureSampleWhenManualSRGBDecodeNeeded(t *testing.T) {
+	_, fragSrc := Compile(&shaderir.Program{
+		FragmentBody:                  "\tgl_FragColor = " + shaderir.TextureSamplePlaceholder + ";",
+		FragmentTextureSample:         "texture(tex, uv)",
+		FragmentNeedsManualSRGBDecode: true,
+	}, 450)
+
+	want := "gl_FragColor = " + WrapManualSRGBDecode("texture(tex, uv)") + ";"
+	if !strings.Contains(fragSrc, want) {
+		t.Errorf("Compile output does not contain %q:\n%s", want, fragSrc)
+	}
+}
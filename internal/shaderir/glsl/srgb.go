@@ -0,0 +1,43 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glsl
+
+import "fmt"
+
+// manualSRGBDecodeFormat is a GLSL expression approximating the sRGB-to-linear decode that
+// GL_SRGB8_ALPHA8 would otherwise perform in hardware.
+const manualSRGBDecodeFormat = "pow(%s, vec4(2.2, 2.2, 2.2, 1.0))"
+
+// manualSRGBEncodeFormat is the inverse of manualSRGBDecodeFormat, used when writing to a
+// framebuffer whose color space is ColorSpaceLinear but isSRGBFramebufferSupported reports false.
+const manualSRGBEncodeFormat = "pow(%s, vec4(1.0/2.2, 1.0/2.2, 1.0/2.2, 1.0))"
+
+// WrapManualSRGBDecode wraps sampleExpr, a GLSL expression sampling a texture (e.g.
+// "texture(tex, uv)"), with the sRGB-to-linear decode a hardware GL_SRGB8_ALPHA8 sampler would
+// otherwise perform. The emitter calls this when building a texture sample against a texture
+// context.manualSRGBDecodeNeeded reports true for, so a linear color space still round-trips
+// correctly on drivers without hardware sRGB texture support (notably GLES2/WebGL1).
+func WrapManualSRGBDecode(sampleExpr string) string {
+	return fmt.Sprintf(manualSRGBDecodeFormat, sampleExpr)
+}
+
+// WrapManualSRGBEncode wraps colorExpr, a GLSL expression producing the linear-space color about
+// to be written to a framebuffer, with the linear-to-sRGB re-encode that fixed-function
+// GL_FRAMEBUFFER_SRGB blending would otherwise perform. Compile does not call it: Program has no
+// field that requests the encode, so applying it to a fragment's output is left to whoever writes
+// FragmentBody.
+func WrapManualSRGBEncode(colorExpr string) string {
+	return fmt.Sprintf(manualSRGBEncodeFormat, colorExpr)
+}
@@ -0,0 +1,33 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glsl
+
+import "testing"
+
+func TestWrapManualSRGBDecode(t *testing.T) {
+	got := WrapManualSRGBDecode("texture(tex, uv)")
+	want := "pow(texture(tex, uv), vec4(2.2, 2.2, 2.2, 1.0))"
+	if got != want {
+		t.Errorf("WrapManualSRGBDecode: got %q, want %q", got, want)
+	}
+}
+
+func TestWrapManualSRGBEncode(t *testing.T) {
+	got := WrapManualSRGBEncode("color")
+	want := "pow(color, vec4(1.0/2.2, 1.0/2.2, 1.0/2.2, 1.0))"
+	if got != want {
+		t.Errorf("WrapManualSRGBEncode: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glsl
+
+// GLSLVersionVulkan requests GLSL 450 targeting Vulkan's GLSL dialect, which differs from the
+// OpenGL dialect Compile otherwise emits in its binding/descriptor-set layout qualifiers and its
+// lack of compatibility-profile fallbacks. Source produced with this version is meant to be fed
+// to a GLSL-to-SPIR-V compiler (see vulkan/vk.CompileGLSLToSPIRV), not run directly.
+const GLSLVersionVulkan GLSLVersion = 450
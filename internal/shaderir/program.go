@@ -0,0 +1,39 @@
+// Copyright 2023 The Ebiten Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shaderir
+
+// TextureSamplePlaceholder is the literal token FragmentBody uses wherever it samples the
+// program's input texture. glsl.Compile substitutes it with either a plain texture() call or one
+// wrapped in the shader-side sRGB decode, depending on FragmentNeedsManualSRGBDecode.
+const TextureSamplePlaceholder = "@TEXTURE_SAMPLE@"
+
+// Program is the IR for a Kage shader's vertex and fragment stages.
+//
+// Like ComputeProgram, VertexBody and FragmentBody are carried as GLSL source text rather than a
+// structured statement/expression tree; see ComputeProgram's doc comment for what that rules out.
+type Program struct {
+	VertexBody   string
+	FragmentBody string
+
+	// FragmentTextureSample is the GLSL expression substituted for TextureSamplePlaceholder in
+	// FragmentBody, e.g. "texture(tex, uv)".
+	FragmentTextureSample string
+
+	// FragmentNeedsManualSRGBDecode marks that FragmentTextureSample must be wrapped in the
+	// shader-side sRGB decode glsl.WrapManualSRGBDecode produces, because the driver that will
+	// create this program's textures has no hardware sRGB sampling support (see
+	// opengl.context.manualSRGBDecodeNeeded).
+	FragmentNeedsManualSRGBDecode bool
+}